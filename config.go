@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/ahydrax/argon-one-pi-controller/internal/button"
+	"github.com/ahydrax/argon-one-pi-controller/internal/fan"
+	"github.com/ahydrax/argon-one-pi-controller/internal/httpapi"
+	"github.com/ahydrax/argon-one-pi-controller/internal/temperature"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultConfigPath is where the daemon looks for its config file when
+// none is given on the command line.
+const defaultConfigPath = "/etc/argononepicontroller/config.yaml"
+
+// Config is the daemon's on-disk configuration. It is loaded at startup
+// and re-read on SIGHUP, when only its fan section is applied to the
+// running controller.
+type Config struct {
+	Fan         fan.Config         `yaml:"fan"`
+	HTTP        HTTPConfig         `yaml:"http"`
+	Temperature temperature.Config `yaml:"temperature"`
+	Button      button.Config      `yaml:"button"`
+}
+
+// HTTPConfig controls the embedded status/metrics server.
+type HTTPConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+// DefaultConfig returns the configuration used when no config file is
+// present.
+func DefaultConfig() Config {
+	return Config{
+		Fan:         fan.DefaultConfig(),
+		HTTP:        HTTPConfig{Enabled: true, Addr: httpapi.DefaultAddr},
+		Temperature: temperature.DefaultConfig(),
+		Button:      button.DefaultConfig(),
+	}
+}
+
+// LoadConfig reads and parses the YAML config file at path. If path does
+// not exist, DefaultConfig is returned unchanged.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,96 @@
+// Package button turns raw GPIO edges from the Argon ONE's shutdown button
+// into debounced, classified press events, and runs whatever shell command
+// the user has mapped to each one.
+package button
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// Event classifies how the button was pressed.
+type Event string
+
+const (
+	ShortPress    Event = "short_press"
+	DoublePress   Event = "double_press"
+	LongPress     Event = "long_press"
+	VeryLongPress Event = "very_long_press"
+)
+
+// Config tunes debouncing/classification and maps each Event to a shell
+// command to run, e.g. "reboot", "shutdown now", "systemctl restart foo",
+// or a path to a custom script.
+type Config struct {
+	Pin int `yaml:"pin"`
+
+	// DebounceInterval rejects edges closer together than this, to filter
+	// out mechanical switch bounce.
+	DebounceInterval time.Duration `yaml:"debounce_interval"`
+
+	// DoublePressWindow is how long the watcher waits after a short press
+	// to see if a second one follows before emitting ShortPress.
+	DoublePressWindow time.Duration `yaml:"double_press_window"`
+
+	LongPressThreshold     time.Duration `yaml:"long_press_threshold"`
+	VeryLongPressThreshold time.Duration `yaml:"very_long_press_threshold"`
+
+	Actions map[Event]string `yaml:"actions"`
+}
+
+// DefaultConfig maps a long press to a reboot and a very long press to a
+// shutdown; short and double presses run nothing.
+func DefaultConfig() Config {
+	return Config{
+		Pin:                    4,
+		DebounceInterval:       50 * time.Millisecond,
+		DoublePressWindow:      400 * time.Millisecond,
+		LongPressThreshold:     3 * time.Second,
+		VeryLongPressThreshold: 7 * time.Second,
+		Actions: map[Event]string{
+			LongPress:     "reboot",
+			VeryLongPress: "shutdown now",
+		},
+	}
+}
+
+// classify maps a press duration to an Event.
+func (cfg Config) classify(pressDuration time.Duration) Event {
+	switch {
+	case pressDuration >= cfg.VeryLongPressThreshold:
+		return VeryLongPress
+	case pressDuration >= cfg.LongPressThreshold:
+		return LongPress
+	default:
+		return ShortPress
+	}
+}
+
+// RunAction runs the shell command mapped to event, if any.
+func (cfg Config) RunAction(event Event) error {
+	command, ok := cfg.Actions[event]
+	if !ok || command == "" {
+		return nil
+	}
+
+	return exec.Command("sh", "-c", command).Run()
+}
+
+// Watcher polls a GPIO pin for edges and turns them into debounced Events.
+type Watcher struct {
+	cfg Config
+	pin rpio.Pin
+}
+
+// NewWatcher builds a Watcher for cfg. It does not touch the GPIO pin
+// until Run is called.
+func NewWatcher(cfg Config) *Watcher {
+	return &Watcher{cfg: cfg, pin: rpio.Pin(cfg.Pin)}
+}
+
+// RunAction runs the shell command mapped to event, if any.
+func (w *Watcher) RunAction(event Event) error {
+	return w.cfg.RunAction(event)
+}
@@ -0,0 +1,32 @@
+package button
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_Classify(t *testing.T) {
+	cfg := DefaultConfig()
+
+	cases := []struct {
+		name     string
+		duration time.Duration
+		want     Event
+	}{
+		{"well under long press", 1 * time.Second, ShortPress},
+		{"just under long press threshold", cfg.LongPressThreshold - time.Millisecond, ShortPress},
+		{"at long press threshold", cfg.LongPressThreshold, LongPress},
+		{"between long and very long", cfg.LongPressThreshold + time.Second, LongPress},
+		{"just under very long press threshold", cfg.VeryLongPressThreshold - time.Millisecond, LongPress},
+		{"at very long press threshold", cfg.VeryLongPressThreshold, VeryLongPress},
+		{"well over very long press threshold", cfg.VeryLongPressThreshold + 5*time.Second, VeryLongPress},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cfg.classify(tc.duration); got != tc.want {
+				t.Errorf("classify(%v) = %v, want %v", tc.duration, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package button
+
+import "time"
+
+// debouncer turns raw pin-level transitions into debounced, classified
+// button events. It holds no GPIO state of its own, so it can be driven by
+// Watcher.Run against real hardware, or by tests against synthetic
+// timestamps.
+type debouncer struct {
+	cfg Config
+
+	pressedAt    time.Time
+	releasedAt   time.Time
+	pendingShort time.Time
+}
+
+func newDebouncer(cfg Config) *debouncer {
+	return &debouncer{cfg: cfg}
+}
+
+// Edge processes one pin transition at now. pressed is true for the pin
+// going high (button down), false for it going low (button up). It returns
+// the event to emit, if any.
+func (d *debouncer) Edge(pressed bool, now time.Time) (Event, bool) {
+	if pressed {
+		if now.Sub(d.releasedAt) >= d.cfg.DebounceInterval {
+			d.pressedAt = now
+		}
+		return "", false
+	}
+
+	if d.pressedAt.IsZero() {
+		return "", false
+	}
+
+	pressDuration := now.Sub(d.pressedAt)
+	d.pressedAt = time.Time{}
+
+	if pressDuration < d.cfg.DebounceInterval {
+		// Bounce on release: too short to be a real press.
+		return "", false
+	}
+	d.releasedAt = now
+
+	event := d.cfg.classify(pressDuration)
+	if event != ShortPress {
+		d.pendingShort = time.Time{}
+		return event, true
+	}
+
+	if !d.pendingShort.IsZero() && now.Sub(d.pendingShort) <= d.cfg.DoublePressWindow {
+		d.pendingShort = time.Time{}
+		return DoublePress, true
+	}
+
+	d.pendingShort = now
+	return "", false
+}
+
+// Tick checks whether a pending short press has waited out its
+// double-press window and should be emitted on its own. Callers should call
+// this periodically between edges.
+func (d *debouncer) Tick(now time.Time) (Event, bool) {
+	if d.pendingShort.IsZero() || now.Sub(d.pendingShort) <= d.cfg.DoublePressWindow {
+		return "", false
+	}
+
+	d.pendingShort = time.Time{}
+	return ShortPress, true
+}
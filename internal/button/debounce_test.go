@@ -0,0 +1,109 @@
+package button
+
+import (
+	"testing"
+	"time"
+)
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.DebounceInterval = 50 * time.Millisecond
+	cfg.DoublePressWindow = 400 * time.Millisecond
+	cfg.LongPressThreshold = 3 * time.Second
+	cfg.VeryLongPressThreshold = 7 * time.Second
+	return cfg
+}
+
+func TestDebouncer_ShortPress(t *testing.T) {
+	d := newDebouncer(testConfig())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if event, ok := d.Edge(true, t0); ok {
+		t.Fatalf("press edge emitted %v, want no event", event)
+	}
+	if event, ok := d.Edge(false, t0.Add(100*time.Millisecond)); ok {
+		t.Fatalf("release edge emitted %v, want pending (double-press window)", event)
+	}
+
+	// Before the double-press window elapses, nothing should fire yet.
+	if event, ok := d.Tick(t0.Add(300 * time.Millisecond)); ok {
+		t.Fatalf("Tick fired %v before double-press window elapsed", event)
+	}
+
+	// Once the window elapses with no second press, the short press fires.
+	event, ok := d.Tick(t0.Add(600 * time.Millisecond))
+	if !ok || event != ShortPress {
+		t.Fatalf("Tick() = (%v, %v), want (%v, true)", event, ok, ShortPress)
+	}
+}
+
+func TestDebouncer_DoublePress(t *testing.T) {
+	d := newDebouncer(testConfig())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.Edge(true, t0)
+	if event, ok := d.Edge(false, t0.Add(100*time.Millisecond)); ok {
+		t.Fatalf("first release emitted %v, want pending", event)
+	}
+
+	d.Edge(true, t0.Add(200*time.Millisecond))
+	event, ok := d.Edge(false, t0.Add(300*time.Millisecond))
+	if !ok || event != DoublePress {
+		t.Fatalf("second release = (%v, %v), want (%v, true)", event, ok, DoublePress)
+	}
+}
+
+func TestDebouncer_LongPress(t *testing.T) {
+	d := newDebouncer(testConfig())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.Edge(true, t0)
+	event, ok := d.Edge(false, t0.Add(3500*time.Millisecond))
+	if !ok || event != LongPress {
+		t.Fatalf("release = (%v, %v), want (%v, true)", event, ok, LongPress)
+	}
+}
+
+func TestDebouncer_VeryLongPress(t *testing.T) {
+	d := newDebouncer(testConfig())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.Edge(true, t0)
+	event, ok := d.Edge(false, t0.Add(8*time.Second))
+	if !ok || event != VeryLongPress {
+		t.Fatalf("release = (%v, %v), want (%v, true)", event, ok, VeryLongPress)
+	}
+}
+
+func TestDebouncer_BouncedReleaseIsIgnored(t *testing.T) {
+	d := newDebouncer(testConfig())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.Edge(true, t0)
+	// Release arrives faster than DebounceInterval: mechanical bounce, not a
+	// real press.
+	if event, ok := d.Edge(false, t0.Add(10*time.Millisecond)); ok {
+		t.Fatalf("bounced release emitted %v, want no event", event)
+	}
+
+	// The bounce must not leave a pending short press behind.
+	if event, ok := d.Tick(t0.Add(time.Second)); ok {
+		t.Fatalf("Tick fired %v after a bounced release, want no event", event)
+	}
+}
+
+func TestDebouncer_RepressTooSoonAfterReleaseIsIgnored(t *testing.T) {
+	d := newDebouncer(testConfig())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.Edge(true, t0)
+	d.Edge(false, t0.Add(100*time.Millisecond))
+
+	// A press arriving within DebounceInterval of the last release is
+	// filtered out, so its eventual "release" has no matching press to
+	// measure a duration from.
+	d.Edge(true, t0.Add(120*time.Millisecond))
+	if event, ok := d.Edge(false, t0.Add(200*time.Millisecond)); ok {
+		t.Fatalf("release after a filtered re-press emitted %v, want no event", event)
+	}
+}
@@ -0,0 +1,45 @@
+package button
+
+import (
+	"context"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// pollInterval is how often Run checks for a new edge. It is far tighter
+// than any of the debounce/classification windows, so it doesn't affect
+// timing accuracy.
+const pollInterval = 10 * time.Millisecond
+
+// Run watches the button pin until ctx is cancelled, calling onEvent for
+// every debounced, classified press. It blocks, so callers should run it
+// in its own goroutine.
+func (w *Watcher) Run(ctx context.Context, onEvent func(Event)) error {
+	rpio.PinMode(w.pin, rpio.Input)
+	rpio.PullMode(w.pin, rpio.PullDown)
+	w.pin.Detect(rpio.AnyEdge)
+	defer w.pin.Detect(rpio.NoEdge)
+
+	d := newDebouncer(w.cfg)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if w.pin.EdgeDetected() {
+			if event, ok := d.Edge(w.pin.Read() == rpio.High, time.Now()); ok {
+				onEvent(event)
+			}
+		}
+
+		if event, ok := d.Tick(time.Now()); ok {
+			onEvent(event)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,205 @@
+// Package fan implements a closed-loop fan speed controller for the Argon
+// ONE case fan, driven either by a stepped temperature curve or by a PID
+// loop.
+package fan
+
+import (
+	"time"
+)
+
+// Mode selects how Controller.Step turns a temperature reading into a duty
+// cycle.
+type Mode string
+
+const (
+	// ModeCurve follows a stepped temperature/duty curve with hysteresis
+	// between tiers so the fan doesn't chatter around a threshold.
+	ModeCurve Mode = "curve"
+	// ModePID drives duty from a PID loop around TempTarget.
+	ModePID Mode = "pid"
+)
+
+// CurvePoint is one tier of a stepped fan curve: at or above TempCelsius the
+// fan is driven at DutyPercent.
+type CurvePoint struct {
+	TempCelsius float64 `yaml:"temp_celsius"`
+	DutyPercent byte    `yaml:"duty_percent"`
+}
+
+// PIDConfig holds the coefficients for ModePID.
+type PIDConfig struct {
+	Kp float64 `yaml:"kp"`
+	Ki float64 `yaml:"ki"`
+	Kd float64 `yaml:"kd"`
+}
+
+// Config describes a Controller's tuning. It is loaded from the daemon's
+// YAML config file so curve points and PID coefficients can be changed
+// without a recompile.
+type Config struct {
+	Mode Mode `yaml:"mode"`
+
+	TempTarget float64 `yaml:"temp_target"`
+	TempMin    float64 `yaml:"temp_min"`
+	TempMax    float64 `yaml:"temp_max"`
+
+	DutyMin byte `yaml:"duty_min"`
+	DutyMax byte `yaml:"duty_max"`
+
+	// HysteresisCelsius is how far temperature must fall back below a
+	// curve tier's threshold before the controller steps duty back down.
+	HysteresisCelsius float64 `yaml:"hysteresis_celsius"`
+
+	// TickInterval is how often Step is expected to be called; it is used
+	// as dt for the PID integral/derivative terms.
+	TickInterval time.Duration `yaml:"tick_interval"`
+
+	Curve []CurvePoint `yaml:"curve"`
+	PID   PIDConfig    `yaml:"pid"`
+}
+
+// DefaultConfig returns the default stepped curve: off below 40C, then
+// 30/55/100% at 45/55/65C.
+func DefaultConfig() Config {
+	return Config{
+		Mode:              ModeCurve,
+		TempTarget:        55,
+		TempMin:           40,
+		TempMax:           70,
+		DutyMin:           0,
+		DutyMax:           100,
+		HysteresisCelsius: 3,
+		TickInterval:      2 * time.Second,
+		Curve: []CurvePoint{
+			{TempCelsius: 40, DutyPercent: 0},
+			{TempCelsius: 45, DutyPercent: 30},
+			{TempCelsius: 55, DutyPercent: 55},
+			{TempCelsius: 65, DutyPercent: 100},
+		},
+		PID: PIDConfig{Kp: 4, Ki: 0.5, Kd: 1},
+	}
+}
+
+// Controller turns temperature samples into a fan duty cycle, smoothing the
+// result so the fan doesn't hunt between speeds.
+type Controller struct {
+	cfg Config
+
+	// DutyCurrent is the duty cycle (0-100) returned by the most recent
+	// Step call.
+	DutyCurrent byte
+
+	tier     int
+	integral float64
+	lastErr  float64
+	hasLast  bool
+}
+
+// NewController builds a Controller from cfg, starting at DutyMin.
+func NewController(cfg Config) *Controller {
+	return &Controller{cfg: cfg, DutyCurrent: cfg.DutyMin}
+}
+
+// SetConfig swaps in a new tuning, e.g. after a config file reload. Duty,
+// tier and PID state are kept so the fan doesn't jump or reset on reload.
+func (c *Controller) SetConfig(cfg Config) {
+	c.cfg = cfg
+
+	// The curve may have shrunk since the last Step call; re-validate the
+	// tier index so the next stepCurve doesn't index out of range.
+	if c.tier >= len(cfg.Curve) {
+		c.tier = len(cfg.Curve) - 1
+	}
+	if c.tier < 0 {
+		c.tier = 0
+	}
+}
+
+// DutyMin returns the configured minimum duty cycle, used by the daemon to
+// spin the fan down before exiting.
+func (c *Controller) DutyMin() byte {
+	return c.cfg.DutyMin
+}
+
+// Step consumes one temperature sample (in Celsius) and returns the duty
+// cycle the fan should be driven at.
+func (c *Controller) Step(temp float64) byte {
+	switch c.cfg.Mode {
+	case ModePID:
+		return c.stepPID(temp)
+	default:
+		return c.stepCurve(temp)
+	}
+}
+
+func (c *Controller) stepCurve(temp float64) byte {
+	curve := c.cfg.Curve
+	if len(curve) == 0 {
+		return c.cfg.DutyMin
+	}
+
+	tier := c.tier
+	for tier < len(curve)-1 && temp >= curve[tier+1].TempCelsius {
+		tier++
+	}
+	for tier > 0 && temp < curve[tier].TempCelsius-c.cfg.HysteresisCelsius {
+		tier--
+	}
+	c.tier = tier
+
+	if temp < curve[0].TempCelsius-c.cfg.HysteresisCelsius {
+		c.DutyCurrent = c.cfg.DutyMin
+		return c.DutyCurrent
+	}
+
+	c.DutyCurrent = clampByte(curve[tier].DutyPercent, c.cfg.DutyMin, c.cfg.DutyMax)
+	return c.DutyCurrent
+}
+
+func (c *Controller) stepPID(temp float64) byte {
+	dt := c.cfg.TickInterval.Seconds()
+	if dt <= 0 {
+		dt = 1
+	}
+
+	err := temp - c.cfg.TempTarget
+
+	derivative := 0.0
+	if c.hasLast {
+		derivative = (err - c.lastErr) / dt
+	}
+	c.lastErr = err
+	c.hasLast = true
+
+	// Tentatively integrate, then undo it if that pushes duty past the
+	// clamp so a sustained error can't wind the integral term up forever.
+	c.integral += err * dt
+	duty := c.cfg.PID.Kp*err + c.cfg.PID.Ki*c.integral + c.cfg.PID.Kd*derivative
+	clamped := clampFloat(duty, float64(c.cfg.DutyMin), float64(c.cfg.DutyMax))
+	if clamped != duty {
+		c.integral -= err * dt
+	}
+
+	c.DutyCurrent = byte(clamped)
+	return c.DutyCurrent
+}
+
+func clampByte(v, min, max byte) byte {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func clampFloat(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
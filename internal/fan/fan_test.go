@@ -0,0 +1,49 @@
+package fan
+
+import "testing"
+
+func TestController_StepCurve(t *testing.T) {
+	cfg := DefaultConfig()
+	c := NewController(cfg)
+
+	if got := c.Step(20); got != 0 {
+		t.Errorf("Step(20) = %d, want 0", got)
+	}
+	if got := c.Step(65); got != 100 {
+		t.Errorf("Step(65) = %d, want 100", got)
+	}
+}
+
+func TestController_SetConfig_ClampsTierWhenCurveShrinks(t *testing.T) {
+	c := NewController(DefaultConfig())
+
+	// Climb to the highest tier of the default 4-point curve.
+	if got := c.Step(70); got != 100 {
+		t.Fatalf("Step(70) = %d, want 100", got)
+	}
+
+	shorter := DefaultConfig()
+	shorter.Curve = []CurvePoint{
+		{TempCelsius: 40, DutyPercent: 0},
+		{TempCelsius: 50, DutyPercent: 50},
+	}
+	c.SetConfig(shorter)
+
+	// Must not panic indexing the now-shorter curve.
+	if got := c.Step(70); got != 50 {
+		t.Errorf("Step(70) after reload = %d, want 50", got)
+	}
+}
+
+func TestController_StepPID_ClampsDuty(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Mode = ModePID
+	c := NewController(cfg)
+
+	for i := 0; i < 10; i++ {
+		duty := c.Step(90)
+		if duty > cfg.DutyMax {
+			t.Fatalf("Step duty %d exceeds DutyMax %d", duty, cfg.DutyMax)
+		}
+	}
+}
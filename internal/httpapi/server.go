@@ -0,0 +1,84 @@
+// Package httpapi exposes the daemon's live status as JSON and as
+// Prometheus metrics so it can be graphed or scraped.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultAddr is the listen address used when none is configured.
+const DefaultAddr = ":9977"
+
+// Server serves /status and /metrics from a State.
+type Server struct {
+	state  *State
+	server *http.Server
+}
+
+// NewServer builds a Server bound to addr. It does not start listening
+// until Run is called.
+func NewServer(addr string, state *State) *Server {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	s := &Server{state: state}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	s.server = &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	return s
+}
+
+// Run starts the HTTP server and blocks until ctx is cancelled, at which
+// point it shuts the server down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return s.server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.state.Snapshot())
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	snap := s.state.Snapshot()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintf(w, "# HELP argonone_cpu_temp_celsius Current CPU temperature in Celsius.\n")
+	fmt.Fprintf(w, "# TYPE argonone_cpu_temp_celsius gauge\n")
+	fmt.Fprintf(w, "argonone_cpu_temp_celsius %f\n", snap.CPUTempCelsius)
+
+	fmt.Fprintf(w, "# HELP argonone_fan_duty_percent Current fan duty cycle, 0-100.\n")
+	fmt.Fprintf(w, "# TYPE argonone_fan_duty_percent gauge\n")
+	fmt.Fprintf(w, "argonone_fan_duty_percent %d\n", snap.FanDutyPercent)
+
+	fmt.Fprintf(w, "# HELP argonone_button_events_total Total shutdown button events handled.\n")
+	fmt.Fprintf(w, "# TYPE argonone_button_events_total counter\n")
+	fmt.Fprintf(w, "argonone_button_events_total %d\n", snap.ButtonEvents)
+}
@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer_HandleStatus(t *testing.T) {
+	state := NewState(55, 40, 70)
+	state.SetTemperature(42)
+	state.SetFanDuty(30)
+
+	srv := NewServer("", state)
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+	srv.server.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var snap Snapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if snap.CPUTempCelsius != 42 {
+		t.Errorf("CPUTempCelsius = %v, want 42", snap.CPUTempCelsius)
+	}
+	if snap.FanDutyPercent != 30 {
+		t.Errorf("FanDutyPercent = %v, want 30", snap.FanDutyPercent)
+	}
+}
+
+func TestServer_HandleMetrics(t *testing.T) {
+	state := NewState(55, 40, 70)
+	state.SetTemperature(42)
+	state.SetFanDuty(30)
+	state.RecordButtonEvent("short_press")
+
+	srv := NewServer("", state)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	srv.server.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"argonone_cpu_temp_celsius 42",
+		"argonone_fan_duty_percent 30",
+		"argonone_button_events_total 1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics body missing %q, got:\n%s", want, body)
+		}
+	}
+}
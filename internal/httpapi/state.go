@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the set of live daemon values the HTTP API reports. It is safe
+// for concurrent use: the temperature/fan/button goroutines write to it and
+// the HTTP handlers read from it.
+type State struct {
+	mu sync.RWMutex
+
+	tempTarget float64
+	tempMin    float64
+	tempMax    float64
+
+	cpuTempCelsius float64
+	fanDutyPercent byte
+
+	lastButtonEvent string
+	buttonEvents    uint64
+
+	startedAt time.Time
+}
+
+// NewState creates a State with the given thresholds, started now.
+func NewState(tempTarget, tempMin, tempMax float64) *State {
+	return &State{
+		tempTarget: tempTarget,
+		tempMin:    tempMin,
+		tempMax:    tempMax,
+		startedAt:  time.Now(),
+	}
+}
+
+// SetTemperature records the most recent CPU temperature sample.
+func (s *State) SetTemperature(celsius float64) {
+	s.mu.Lock()
+	s.cpuTempCelsius = celsius
+	s.mu.Unlock()
+}
+
+// SetFanDuty records the most recent fan duty cycle (0-100).
+func (s *State) SetFanDuty(percent byte) {
+	s.mu.Lock()
+	s.fanDutyPercent = percent
+	s.mu.Unlock()
+}
+
+// RecordButtonEvent records a button event name (e.g. "short_press") and
+// bumps the button event counter.
+func (s *State) RecordButtonEvent(name string) {
+	s.mu.Lock()
+	s.lastButtonEvent = name
+	s.buttonEvents++
+	s.mu.Unlock()
+}
+
+// Snapshot is a point-in-time, JSON-friendly copy of State.
+type Snapshot struct {
+	CPUTempCelsius  float64       `json:"cpu_temp_celsius"`
+	FanDutyPercent  byte          `json:"fan_duty_percent"`
+	TempTarget      float64       `json:"temp_target_celsius"`
+	TempMin         float64       `json:"temp_min_celsius"`
+	TempMax         float64       `json:"temp_max_celsius"`
+	Uptime          time.Duration `json:"uptime"`
+	LastButtonEvent string        `json:"last_button_event"`
+	ButtonEvents    uint64        `json:"button_events_total"`
+}
+
+// Snapshot returns a consistent copy of the current state.
+func (s *State) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return Snapshot{
+		CPUTempCelsius:  s.cpuTempCelsius,
+		FanDutyPercent:  s.fanDutyPercent,
+		TempTarget:      s.tempTarget,
+		TempMin:         s.tempMin,
+		TempMax:         s.tempMax,
+		Uptime:          time.Since(s.startedAt),
+		LastButtonEvent: s.lastButtonEvent,
+		ButtonEvents:    s.buttonEvents,
+	}
+}
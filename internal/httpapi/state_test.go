@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestState_Snapshot_FieldMapping(t *testing.T) {
+	s := NewState(55, 40, 70)
+	s.SetTemperature(48.5)
+	s.SetFanDuty(30)
+	s.RecordButtonEvent("short_press")
+
+	snap := s.Snapshot()
+
+	if snap.CPUTempCelsius != 48.5 {
+		t.Errorf("CPUTempCelsius = %v, want 48.5", snap.CPUTempCelsius)
+	}
+	if snap.FanDutyPercent != 30 {
+		t.Errorf("FanDutyPercent = %v, want 30", snap.FanDutyPercent)
+	}
+	if snap.TempTarget != 55 || snap.TempMin != 40 || snap.TempMax != 70 {
+		t.Errorf("thresholds = %v/%v/%v, want 55/40/70", snap.TempTarget, snap.TempMin, snap.TempMax)
+	}
+	if snap.LastButtonEvent != "short_press" {
+		t.Errorf("LastButtonEvent = %q, want %q", snap.LastButtonEvent, "short_press")
+	}
+	if snap.ButtonEvents != 1 {
+		t.Errorf("ButtonEvents = %d, want 1", snap.ButtonEvents)
+	}
+}
+
+func TestState_RecordButtonEvent_CountsEach(t *testing.T) {
+	s := NewState(55, 40, 70)
+	s.RecordButtonEvent("short_press")
+	s.RecordButtonEvent("long_press")
+
+	snap := s.Snapshot()
+	if snap.ButtonEvents != 2 {
+		t.Errorf("ButtonEvents = %d, want 2", snap.ButtonEvents)
+	}
+	if snap.LastButtonEvent != "long_press" {
+		t.Errorf("LastButtonEvent = %q, want %q", snap.LastButtonEvent, "long_press")
+	}
+}
+
+func TestState_ConcurrentAccess(t *testing.T) {
+	s := NewState(55, 40, 70)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			s.SetTemperature(50)
+		}()
+		go func() {
+			defer wg.Done()
+			s.SetFanDuty(50)
+		}()
+		go func() {
+			defer wg.Done()
+			s.RecordButtonEvent("short_press")
+		}()
+	}
+	wg.Wait()
+
+	if got := s.Snapshot().ButtonEvents; got != 50 {
+		t.Errorf("ButtonEvents = %d, want 50", got)
+	}
+}
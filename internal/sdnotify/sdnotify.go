@@ -0,0 +1,57 @@
+// Package sdnotify sends the sd_notify(3) protocol messages systemd
+// expects from a Type=notify unit: readiness, a periodic watchdog
+// heartbeat, status text, and a stopping notice. It talks to the
+// NOTIFY_SOCKET unix datagram socket directly, so it needs no cgo and no
+// systemd development headers.
+package sdnotify
+
+import (
+	"net"
+	"os"
+)
+
+// Notify sends a raw sd_notify state string, e.g. "READY=1". It is a
+// no-op, returning (false, nil), when the process was not started by
+// systemd (NOTIFY_SOCKET is unset) so the daemon still runs fine outside
+// of systemd.
+func Notify(state string) (bool, error) {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" {
+		return false, nil
+	}
+
+	addr := &net.UnixAddr{Name: socketPath, Net: "unixgram"}
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Ready tells systemd the daemon has finished starting up.
+func Ready() (bool, error) {
+	return Notify("READY=1")
+}
+
+// Stopping tells systemd the daemon is beginning a graceful shutdown.
+func Stopping() (bool, error) {
+	return Notify("STOPPING=1")
+}
+
+// Watchdog pings systemd's watchdog timer. It must be called at less than
+// half of the unit's WatchdogSec or systemd will consider the daemon
+// hung and restart it.
+func Watchdog() (bool, error) {
+	return Notify("WATCHDOG=1")
+}
+
+// Status sets the free-form status text shown by `systemctl status`.
+func Status(text string) (bool, error) {
+	return Notify("STATUS=" + text)
+}
@@ -0,0 +1,69 @@
+package temperature
+
+import (
+	"fmt"
+
+	"github.com/corrupt/go-smbus"
+)
+
+// Kind selects which Source implementation Config builds.
+type Kind string
+
+const (
+	KindThermalZone Kind = "thermal_zone"
+	KindI2C         Kind = "i2c"
+	KindMulti       Kind = "multi"
+)
+
+// Config describes how to build a Source from the daemon's config file.
+type Config struct {
+	Source Kind `yaml:"source"`
+
+	ThermalZonePath string `yaml:"thermal_zone_path"`
+
+	I2CBus     uint `yaml:"i2c_bus"`
+	I2CAddress byte `yaml:"i2c_address"`
+
+	Multi []Config `yaml:"multi"`
+}
+
+// DefaultConfig reads the SoC's own thermal zone, matching the daemon's
+// original vcgencmd-based behaviour.
+func DefaultConfig() Config {
+	return Config{Source: KindThermalZone, ThermalZonePath: DefaultThermalZonePath}
+}
+
+// NewSource builds the Source described by cfg. For KindI2C it opens its
+// own SMBus connection on cfg.I2CBus, independent of any bus already
+// opened for the fan.
+func NewSource(cfg Config) (Source, error) {
+	switch cfg.Source {
+	case KindI2C:
+		address := cfg.I2CAddress
+		if address == 0 {
+			address = DefaultMCP9808Address
+		}
+
+		bus, err := smbus.New(cfg.I2CBus, address)
+		if err != nil {
+			return nil, fmt.Errorf("temperature: opening i2c bus %d: %w", cfg.I2CBus, err)
+		}
+
+		return NewI2CSource(bus), nil
+
+	case KindMulti:
+		sources := make([]Source, 0, len(cfg.Multi))
+		for _, sub := range cfg.Multi {
+			source, err := NewSource(sub)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, source)
+		}
+
+		return NewMultiSource(sources...), nil
+
+	default:
+		return NewThermalZoneSource(cfg.ThermalZonePath), nil
+	}
+}
@@ -0,0 +1,53 @@
+package temperature
+
+import (
+	"github.com/corrupt/go-smbus"
+)
+
+// DefaultMCP9808Address is the MCP9808's factory-default I2C address.
+const DefaultMCP9808Address byte = 0x18
+
+const mcp9808AmbientTempRegister byte = 0x05
+
+// I2CSource reads an MCP9808-compatible temperature sensor over an SMBus
+// already bound to that sensor's address, such as one wired onto the same
+// bus the fan controller uses.
+type I2CSource struct {
+	bus *smbus.SMBus
+}
+
+// NewI2CSource builds an I2CSource reading the sensor bus is bound to.
+func NewI2CSource(bus *smbus.SMBus) *I2CSource {
+	return &I2CSource{bus: bus}
+}
+
+// Read implements Source.
+func (s *I2CSource) Read() (float64, error) {
+	raw, err := s.bus.Read_word_data(mcp9808AmbientTempRegister)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeMCP9808(raw), nil
+}
+
+// decodeMCP9808 converts a raw ambient-temperature register read into
+// Celsius, per the MCP9808 datasheet's upper-byte/lower-byte encoding.
+func decodeMCP9808(raw uint16) float64 {
+	// The MCP9808 sends the 16-bit register big-endian, but smbus words are
+	// read little-endian, so the two bytes arrive swapped.
+	word := ((raw & 0xff) << 8) | (raw >> 8)
+
+	upperByte := (word >> 8) & 0x1f
+	lowerByte := word & 0xff
+
+	celsius := float64(upperByte&0x0f)*16 + float64(lowerByte)/16
+	if upperByte&0x10 != 0 {
+		// Sign bit set: value is negative, expressed as 13-bit two's
+		// complement. The sign bit itself must not be counted as part of
+		// the magnitude above.
+		celsius -= 256
+	}
+
+	return celsius
+}
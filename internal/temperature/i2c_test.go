@@ -0,0 +1,24 @@
+package temperature
+
+import "testing"
+
+func TestDecodeMCP9808(t *testing.T) {
+	cases := []struct {
+		name    string
+		rawWord uint16 // as returned by Read_word_data (byte-swapped on the wire)
+		want    float64
+	}{
+		{name: "zero", rawWord: 0x0000, want: 0},
+		{name: "positive, +25C datasheet example", rawWord: 0x9001, want: 25},
+		{name: "negative, -25.0625C datasheet example", rawWord: 0x6f1e, want: -25.0625},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := decodeMCP9808(tc.rawWord)
+			if got != tc.want {
+				t.Errorf("decodeMCP9808(0x%04x) = %v, want %v", tc.rawWord, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package temperature provides the daemon's TemperatureSource abstraction,
+// so the fan controller can be driven from the SoC's thermal zone, an
+// external I2C probe, or the hotter of several sensors, instead of being
+// hard-wired to shelling out to vcgencmd.
+package temperature
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Source reads a single temperature in Celsius.
+type Source interface {
+	Read() (float64, error)
+}
+
+// DefaultThermalZonePath is where the Pi's SoC temperature is exposed by
+// the kernel.
+const DefaultThermalZonePath = "/sys/class/thermal/thermal_zone0/temp"
+
+// ThermalZoneSource reads a Linux thermal_zone sysfs node, which reports
+// millidegrees Celsius as a plain integer.
+type ThermalZoneSource struct {
+	path string
+}
+
+// NewThermalZoneSource builds a ThermalZoneSource reading path. An empty
+// path falls back to DefaultThermalZonePath.
+func NewThermalZoneSource(path string) *ThermalZoneSource {
+	if path == "" {
+		path = DefaultThermalZonePath
+	}
+	return &ThermalZoneSource{path: path}
+}
+
+// Read implements Source.
+func (s *ThermalZoneSource) Read() (float64, error) {
+	raw, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return 0, fmt.Errorf("temperature: reading %s: %w", s.path, err)
+	}
+
+	milliCelsius, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, fmt.Errorf("temperature: parsing %s: %w", s.path, err)
+	}
+
+	return float64(milliCelsius) / 1000, nil
+}
+
+// MultiSource reads every underlying Source and reports the highest
+// temperature, so the fan reacts to whichever monitored component is
+// hottest.
+type MultiSource struct {
+	sources []Source
+}
+
+// NewMultiSource builds a MultiSource over sources.
+func NewMultiSource(sources ...Source) *MultiSource {
+	return &MultiSource{sources: sources}
+}
+
+// Read implements Source. It fails only if every underlying source fails.
+func (m *MultiSource) Read() (float64, error) {
+	var (
+		max     float64
+		ok      bool
+		lastErr error
+	)
+
+	for _, source := range m.sources {
+		temp, err := source.Read()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if !ok || temp > max {
+			max = temp
+			ok = true
+		}
+	}
+
+	if !ok {
+		if lastErr == nil {
+			return 0, fmt.Errorf("temperature: no sources configured")
+		}
+		return 0, fmt.Errorf("temperature: all sources failed: %w", lastErr)
+	}
+
+	return max, nil
+}
@@ -0,0 +1,90 @@
+package temperature
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestThermalZoneSource_Read(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "temp")
+	if err := ioutil.WriteFile(path, []byte("45123\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	s := NewThermalZoneSource(path)
+	got, err := s.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got != 45.123 {
+		t.Errorf("Read() = %v, want 45.123", got)
+	}
+}
+
+func TestThermalZoneSource_Read_MissingFile(t *testing.T) {
+	s := NewThermalZoneSource(filepath.Join(t.TempDir(), "missing"))
+	if _, err := s.Read(); err == nil {
+		t.Error("Read() error = nil, want non-nil")
+	}
+}
+
+type fakeSource struct {
+	temp float64
+	err  error
+}
+
+func (f fakeSource) Read() (float64, error) {
+	return f.temp, f.err
+}
+
+func TestMultiSource_Read_PicksHottest(t *testing.T) {
+	m := NewMultiSource(
+		fakeSource{temp: 40},
+		fakeSource{temp: 65},
+		fakeSource{temp: 50},
+	)
+
+	got, err := m.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got != 65 {
+		t.Errorf("Read() = %v, want 65", got)
+	}
+}
+
+func TestMultiSource_Read_SkipsFailedSources(t *testing.T) {
+	m := NewMultiSource(
+		fakeSource{err: errors.New("sensor offline")},
+		fakeSource{temp: 55},
+	)
+
+	got, err := m.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got != 55 {
+		t.Errorf("Read() = %v, want 55", got)
+	}
+}
+
+func TestMultiSource_Read_AllSourcesFail(t *testing.T) {
+	wantErr := errors.New("sensor offline")
+	m := NewMultiSource(fakeSource{err: wantErr})
+
+	_, err := m.Read()
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Read() error = %v, want wrapping %v", err, wantErr)
+	}
+}
+
+func TestMultiSource_Read_NoSources(t *testing.T) {
+	m := NewMultiSource()
+
+	if _, err := m.Read(); err == nil {
+		t.Error("Read() error = nil, want non-nil")
+	}
+}
@@ -1,27 +1,34 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
-	"github.com/corrupt/go-smbus"
-	"github.com/stianeikeland/go-rpio/v4"
-	"github.com/takama/daemon"
+	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"os/signal"
-	"strconv"
-	"strings"
+	"sync"
 	"syscall"
 	"time"
+
+	"github.com/ahydrax/argon-one-pi-controller/internal/button"
+	"github.com/ahydrax/argon-one-pi-controller/internal/fan"
+	"github.com/ahydrax/argon-one-pi-controller/internal/httpapi"
+	"github.com/ahydrax/argon-one-pi-controller/internal/sdnotify"
+	"github.com/ahydrax/argon-one-pi-controller/internal/temperature"
+	"github.com/corrupt/go-smbus"
+	"github.com/stianeikeland/go-rpio/v4"
+	"github.com/takama/daemon"
 )
 
 const (
-	name            = "Argon One Pi Controller"
-	description     = "Watches shutdown button and temperature"
-	shutdownPin     = 4
+	name                 = "Argon One Pi Controller"
+	description          = "Watches shutdown button and temperature"
 	smbusFanAddress byte = 0x1a
+
+	// shutdownDeadline bounds how long Manage waits for goroutines to
+	// drain on a graceful shutdown before forcing the process to exit.
+	shutdownDeadline = 10 * time.Second
 )
 
 var dependencies = []string{"multi-user.target"}
@@ -41,11 +48,23 @@ func (service *Service) Manage() (string, error) {
 
 	usage := "Usage: argononepicontroller install | remove | start | stop | status"
 
+	cfg, err := LoadConfig(defaultConfigPath)
+	if err != nil {
+		return "failed loading config", err
+	}
+
 	if len(os.Args) > 1 {
 		command := os.Args[1]
 		switch command {
 		case "install":
-			return service.Install()
+			status, err := service.Install()
+			if err != nil {
+				return status, err
+			}
+			if err := installSystemdUnit(cfg.Fan.TickInterval); err != nil {
+				return "installed but failed to configure systemd watchdog", err
+			}
+			return status, nil
 		case "remove":
 			return service.Remove()
 		case "start":
@@ -59,8 +78,7 @@ func (service *Service) Manage() (string, error) {
 		}
 	}
 
-	err := rpio.Open()
-	if err != nil {
+	if err := rpio.Open(); err != nil {
 		return "failed opening gpio", err
 	}
 
@@ -69,31 +87,125 @@ func (service *Service) Manage() (string, error) {
 		return "failed opening smbus", err
 	}
 
-	osInterrupt := make(chan os.Signal, 1)
-	signal.Notify(osInterrupt, os.Interrupt, os.Kill, syscall.SIGABRT, syscall.SIGTERM)
+	fanController := fan.NewController(cfg.Fan)
+	apiState := httpapi.NewState(cfg.Fan.TempTarget, cfg.Fan.TempMin, cfg.Fan.TempMax)
+
+	tempSource, err := temperature.NewSource(cfg.Temperature)
+	if err != nil {
+		return "failed opening temperature source", err
+	}
 
-	var osSignals = make(chan os.Signal, 1)
-	signal.Notify(osSignals)
+	sigChannel := make(chan os.Signal, 1)
+	signal.Notify(sigChannel, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	signal.Ignore(syscall.SIGCHLD, syscall.SIGPIPE)
 
 	var tempChannel = make(chan float64, 1)
 	var errChannel = make(chan error, 1)
 
 	appCtx, cancel := context.WithCancel(context.Background())
 
-	go monitorTemperature(appCtx, tempChannel, errChannel)
-	go handleTemperature(appCtx, tempChannel, smbus, errChannel)
-	go watchShutdownButton(appCtx, errChannel)
+	var wg sync.WaitGroup
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		monitorTemperature(appCtx, tempSource, cfg.Fan.TickInterval, tempChannel, errChannel, apiState)
+	}()
+	go func() {
+		defer wg.Done()
+		handleTemperature(appCtx, tempChannel, smbus, fanController, errChannel, apiState)
+	}()
+	buttonWatcher := button.NewWatcher(cfg.Button)
+	go func() {
+		defer wg.Done()
+		watchShutdownButton(appCtx, buttonWatcher, errChannel, apiState)
+	}()
+
+	if cfg.HTTP.Enabled {
+		apiServer := httpapi.NewServer(cfg.HTTP.Addr, apiState)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := apiServer.Run(appCtx); err != nil {
+				errChannel <- err
+			}
+		}()
+	}
+
+	if _, err := sdnotify.Ready(); err != nil {
+		stdlog.Println("sd_notify READY failed: ", err)
+	}
+
+	for {
+		select {
+		case sig := <-sigChannel:
+			if sig == syscall.SIGHUP {
+				reloadConfig(fanController, defaultConfigPath)
+				continue
+			}
+
+			stdlog.Println("Got signal: ", sig)
+			return gracefulShutdown(cancel, &wg, smbus, fanController)
+
+		case err := <-errChannel:
+			cancel()
+			return "failed", err
+		}
+	}
+}
+
+// reloadConfig re-reads the config file at path and applies the new fan
+// curve/PID tuning in place, without restarting the daemon.
+func reloadConfig(controller *fan.Controller, path string) {
+	stdlog.Println("SIGHUP received, reloading config")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		errlog.Println("config reload failed: ", err)
+		return
+	}
+
+	controller.SetConfig(cfg.Fan)
+
+	if _, err := sdnotify.Status("reloaded config"); err != nil {
+		stdlog.Println("sd_notify STATUS failed: ", err)
+	}
+}
+
+// gracefulShutdown cancels the running goroutines, waits up to
+// shutdownDeadline for them to drain, spins the fan down to its minimum
+// duty, and releases the gpio/smbus handles before returning.
+func gracefulShutdown(cancel context.CancelFunc, wg *sync.WaitGroup, bus *smbus.SMBus, controller *fan.Controller) (string, error) {
+	if _, err := sdnotify.Stopping(); err != nil {
+		stdlog.Println("sd_notify STOPPING failed: ", err)
+	}
+
+	cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
 
 	select {
-	case killSignal := <-osInterrupt:
-		stdlog.Println("Got signal: ", killSignal)
-		cancel()
-		return "Process finished", nil
+	case <-drained:
+	case <-time.After(shutdownDeadline):
+		errlog.Println("shutdown: goroutines did not drain within", shutdownDeadline, ", forcing exit")
+	}
 
-	case err := <-errChannel:
-		cancel()
-		return "failed", err
+	dutyBytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(dutyBytes, uint32(controller.DutyMin()))
+	if _, err := bus.Write_block_data(smbusFanAddress, dutyBytes); err != nil {
+		errlog.Println("failed spinning fan down: ", err)
 	}
+
+	if err := bus.Bus_close(); err != nil {
+		errlog.Println("failed closing smbus: ", err)
+	}
+	rpio.Close()
+
+	return "Process finished", nil
 }
 
 func main() {
@@ -113,51 +225,36 @@ func main() {
 	stdlog.Println(status)
 }
 
-func monitorTemperature(ctx context.Context, tempChannel chan float64, errCh chan error) {
+func monitorTemperature(ctx context.Context, source temperature.Source, tickInterval time.Duration, tempChannel chan float64, errCh chan error, apiState *httpapi.State) {
 	for {
 		select {
 		case <-ctx.Done():
 			stdlog.Println("temperature-watch: cancellation requested")
 			return
 		default:
-			temp, err := getCurrentTemperature()
+			temp, err := source.Read()
 			if err != nil {
 				errCh <- err
 				return
 			}
 
+			apiState.SetTemperature(temp)
 			tempChannel <- temp
-			time.Sleep(5 * time.Second)
-		}
-	}
-}
-
-func getCurrentTemperature() (float64, error) {
-
-	getTempCommand := exec.Command("vcgencmd", "measure_temp")
-	var commandStdout bytes.Buffer
 
-	getTempCommand.Stdout = &commandStdout
-
-	err := getTempCommand.Run()
-	if err != nil {
-		return 0, err
-	}
-
-	commandResult := string(commandStdout.Bytes())
-
-	commandResult = strings.Replace(commandResult, "temp=", "", 1)
-	commandResult = strings.Replace(commandResult, "'C", "", 1)
+			snap := apiState.Snapshot()
+			if _, err := sdnotify.Status(fmt.Sprintf("temp=%.1fC duty=%d%%", snap.CPUTempCelsius, snap.FanDutyPercent)); err != nil {
+				stdlog.Println("sd_notify STATUS failed: ", err)
+			}
+			if _, err := sdnotify.Watchdog(); err != nil {
+				stdlog.Println("sd_notify WATCHDOG failed: ", err)
+			}
 
-	parsedFloat, err := strconv.ParseFloat(commandResult, 64)
-	if err != nil {
-		return 0, err
+			time.Sleep(tickInterval)
+		}
 	}
-
-	return parsedFloat, nil
 }
 
-func handleTemperature(ctx context.Context, channel chan float64, bus *smbus.SMBus, errCh chan error) {
+func handleTemperature(ctx context.Context, channel chan float64, bus *smbus.SMBus, controller *fan.Controller, errCh chan error, apiState *httpapi.State) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -165,60 +262,31 @@ func handleTemperature(ctx context.Context, channel chan float64, bus *smbus.SMB
 			return
 
 		case temp := <-channel:
-			if temp > 50 {
-				fanSpeedBytes := make([]byte,4)
-				binary.LittleEndian.PutUint32(fanSpeedBytes, 100)
-				bus.Write_block_data(smbusFanAddress, fanSpeedBytes)
+			duty := controller.Step(temp)
+			apiState.SetFanDuty(duty)
+
+			fanSpeedBytes := make([]byte, 4)
+			binary.LittleEndian.PutUint32(fanSpeedBytes, uint32(duty))
+			if _, err := bus.Write_block_data(smbusFanAddress, fanSpeedBytes); err != nil {
+				errCh <- err
+				return
 			}
 		}
 	}
 }
 
-func watchShutdownButton(ctx context.Context, errCh chan error) {
+func watchShutdownButton(ctx context.Context, watcher *button.Watcher, errCh chan error, apiState *httpapi.State) {
+	err := watcher.Run(ctx, func(event button.Event) {
+		stdlog.Println("button event: ", event)
+		apiState.RecordButtonEvent(string(event))
 
-	var rebootCommand = exec.Command("reboot")
-	var shutdownCommand = exec.Command("shutdown", "now")
-
-	err := rpio.Open()
+		if err := watcher.RunAction(event); err != nil {
+			errCh <- err
+		}
+	})
 	if err != nil {
 		errCh <- err
-		return
 	}
 
-	var shutdownPin = rpio.Pin(shutdownPin)
-	rpio.PinMode(shutdownPin, rpio.Input)
-	rpio.PullMode(shutdownPin, rpio.PullDown)
-
-	for {
-		select {
-		case <-ctx.Done():
-			stdlog.Println("button-watch: cancellation requested")
-			return
-
-		default:
-			var sleepTime = time.Millisecond * 100
-			var tick = 0.1
-			var pulseTime = tick
-			shutdownPin.Detect(rpio.RiseEdge)
-			time.Sleep(sleepTime)
-			for shutdownPin.Read() == rpio.High {
-				time.Sleep(sleepTime)
-				pulseTime += tick
-			}
-
-			if pulseTime >= 2 || pulseTime <= 3 {
-				err := rebootCommand.Run()
-				if err != nil {
-					errCh <- err
-				}
-			}
-
-			if pulseTime >= 4 {
-				err := shutdownCommand.Run()
-				if err != nil {
-					errCh <- err
-				}
-			}
-		}
-	}
+	stdlog.Println("button-watch: cancellation requested")
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahydrax/argon-one-pi-controller/internal/fan"
+)
+
+// TestReloadConfig_SwapsFanConfigWithoutDroppingState exercises the SIGHUP
+// path end to end: LoadConfig parsing a file on disk, then
+// Controller.SetConfig applying it in place. It reproduces the curve-shrink
+// scenario fan_test.go covers directly, but through reloadConfig itself so
+// the YAML wiring is covered too.
+func TestReloadConfig_SwapsFanConfigWithoutDroppingState(t *testing.T) {
+	controller := fan.NewController(fan.DefaultConfig())
+
+	// Climb to the top tier of the default 4-point curve.
+	if got := controller.Step(70); got != 100 {
+		t.Fatalf("Step(70) = %d, want 100", got)
+	}
+
+	const shorterCurveYAML = `
+fan:
+  mode: curve
+  temp_target: 55
+  temp_min: 40
+  temp_max: 70
+  duty_min: 0
+  duty_max: 100
+  hysteresis_celsius: 3
+  tick_interval: 2000000000
+  curve:
+    - temp_celsius: 40
+      duty_percent: 0
+    - temp_celsius: 50
+      duty_percent: 50
+`
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(shorterCurveYAML), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	reloadConfig(controller, path)
+
+	// The reload must keep the duty the controller was already driving.
+	if controller.DutyCurrent != 100 {
+		t.Errorf("DutyCurrent after SIGHUP reload = %d, want 100", controller.DutyCurrent)
+	}
+
+	// Must not panic indexing the now-shorter curve, and must reflect the
+	// reloaded curve rather than silently keeping the old one.
+	if got := controller.Step(70); got != 50 {
+		t.Errorf("Step(70) after SIGHUP reload = %d, want 50", got)
+	}
+}
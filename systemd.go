@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"text/template"
+	"time"
+
+	"github.com/ahydrax/argon-one-pi-controller/internal/fan"
+)
+
+// systemdUnitPath is where the watchdog-enabled unit is written after
+// takama/daemon installs its own, simpler one. takama/daemon has no way to
+// express Type=notify/WatchdogSec, so the daemon writes the file it needs
+// itself and asks systemd to reload.
+const systemdUnitPath = "/etc/systemd/system/" + name + ".service"
+
+// minWatchdogSec is the smallest watchdog timeout installSystemdUnit will
+// ever configure, regardless of how fast the configured fan tick interval
+// is, so a slow disk or a momentary scheduling hiccup can't trip it.
+const minWatchdogSec = 10 * time.Second
+
+// watchdogMargin is how many sampling ticks systemd tolerates missing
+// before it kills and restarts the process. monitorTemperature pings the
+// watchdog once per TickInterval, so WatchdogSec needs to stay a multiple
+// of that cadence, not a fixed constant.
+const watchdogMargin = 3
+
+var systemdUnitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description={{.Description}}
+After=network.target
+
+[Service]
+Type=notify
+ExecStart={{.ExecPath}}
+WatchdogSec={{.WatchdogSec}}
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+type systemdUnitData struct {
+	Description string
+	ExecPath    string
+	WatchdogSec string
+}
+
+// watchdogInterval derives the systemd WatchdogSec from the configured fan
+// tick interval, so raising tick_interval in the config can't silently turn
+// into a watchdog restart loop.
+func watchdogInterval(tickInterval time.Duration) time.Duration {
+	if tickInterval <= 0 {
+		tickInterval = fan.DefaultConfig().TickInterval
+	}
+
+	watchdog := tickInterval * watchdogMargin
+	if watchdog < minWatchdogSec {
+		watchdog = minWatchdogSec
+	}
+	return watchdog
+}
+
+// installSystemdUnit overwrites the unit file takama/daemon just installed
+// with one that opts into systemd's watchdog and restart-on-failure, then
+// reloads systemd so the change takes effect. tickInterval is the
+// configured fan sampling interval, used to size WatchdogSec so it stays
+// ahead of the cadence monitorTemperature actually pings at.
+func installSystemdUnit(tickInterval time.Duration) error {
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(systemdUnitPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := systemdUnitData{
+		Description: description,
+		ExecPath:    execPath,
+		WatchdogSec: fmt.Sprintf("%ds", int(watchdogInterval(tickInterval).Seconds())),
+	}
+	if err := systemdUnitTemplate.Execute(f, data); err != nil {
+		return err
+	}
+
+	return exec.Command("systemctl", "daemon-reload").Run()
+}